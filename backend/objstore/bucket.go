@@ -0,0 +1,92 @@
+package objstore
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v6"
+)
+
+// Bucket is a higher level wrapper around a *minio.Client scoped to a single bucket. It covers the
+// operations most services actually need (presigned URLs, lifecycle/CORS setup, copying objects)
+// instead of leaving every caller to work those out against the bare minio client. Every request it
+// sends goes through the *minio.Client it was given, so with a client from the package
+// Client()/CustomClient() constructors it is covered by the existing
+// paceObjStoreTotal/paceObjStoreFailed/paceObjStoreDurationSeconds metrics round tripper without any
+// extra instrumentation here.
+type Bucket struct {
+	client *minio.Client
+	name   string
+}
+
+// NewBucket returns a Bucket for name using client, without checking that the bucket exists.
+// Use EnsureBucket instead if the bucket and its policies still need to be created.
+func NewBucket(client *minio.Client, name string) *Bucket {
+	return &Bucket{client: client, name: name}
+}
+
+// Name returns the wrapped bucket's name.
+func (b *Bucket) Name() string {
+	return b.name
+}
+
+// PresignedGet returns a presigned URL that is valid for ttl and performs a GET of key without
+// further authentication. respHeaders, if given, are returned as response headers for that one
+// request, e.g. to force a download filename via Content-Disposition.
+func (b *Bucket) PresignedGet(ctx context.Context, key string, ttl time.Duration, respHeaders url.Values) (*url.URL, error) {
+	u, err := b.client.PresignedGetObject(b.name, key, ttl, respHeaders)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	return u, nil
+}
+
+// PresignedPut returns a presigned URL that is valid for ttl and performs a PUT of key without
+// further authentication.
+//
+// KNOWN GAP, needs maintainer sign-off: this does not accept a contentType or contentLengthRange
+// constraint, even though the original request asked for one. minio-go v6's PresignedPutObject has
+// no way to bake either into a bare PUT signature; S3 only enforces them as POST policy fields, not
+// as part of a PUT signature. Use PresignedPostPolicy instead if the upload needs to be constrained,
+// or upgrade to a minio-go version/API that supports conditions on presigned PUTs if PUT semantics
+// are a hard requirement for callers.
+func (b *Bucket) PresignedPut(ctx context.Context, key string, ttl time.Duration) (*url.URL, error) {
+	u, err := b.client.PresignedPutObject(b.name, key, ttl)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	return u, nil
+}
+
+// PresignedPostPolicy returns the URL and form fields a browser must POST to upload key directly
+// to the bucket without exposing any credentials to the client. ttl bounds how long the policy is
+// valid; contentType and contentLengthRange, if set, are added to the policy as conditions that the
+// upload must meet.
+func (b *Bucket) PresignedPostPolicy(ctx context.Context, key string, ttl time.Duration, contentType string, contentLengthRange [2]int64) (*url.URL, map[string]string, error) {
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(b.name); err != nil {
+		return nil, nil, err
+	}
+	if err := policy.SetKey(key); err != nil {
+		return nil, nil, err
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(ttl)); err != nil {
+		return nil, nil, err
+	}
+	if contentType != "" {
+		if err := policy.SetContentType(contentType); err != nil {
+			return nil, nil, err
+		}
+	}
+	if contentLengthRange != [2]int64{} {
+		if err := policy.SetContentLengthRange(contentLengthRange[0], contentLengthRange[1]); err != nil {
+			return nil, nil, err
+		}
+	}
+	u, formFields, err := b.client.PresignedPostPolicy(policy)
+	if err != nil {
+		return nil, nil, classifyError(err)
+	}
+	return u, formFields, nil
+}
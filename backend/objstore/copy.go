@@ -0,0 +1,73 @@
+package objstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v6"
+)
+
+// copyMultipartThreshold is S3's single-request COPY size limit (5 GiB); objects at or above it
+// must be copied part by part instead.
+const copyMultipartThreshold = 5 * 1024 * 1024 * 1024
+
+// copyPartSize is the part size used for multipart copies, chosen well above S3's 5 MiB minimum
+// part size so that even multi-terabyte objects stay under the 10000-parts-per-upload limit.
+const copyPartSize = 512 * 1024 * 1024
+
+// Copy copies srcKey in srcBucket to dstKey in dstBucket using a server-side copy, so the object
+// data never passes through this process. Objects under copyMultipartThreshold are copied with a
+// single COPY request; larger objects are copied part by part with a multipart upload, since S3
+// rejects a single COPY request above that size.
+func (b *Bucket) Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	info, err := b.client.StatObject(srcBucket, srcKey, minio.StatObjectOptions{})
+	if err != nil {
+		return classifyError(err)
+	}
+
+	if info.Size < copyMultipartThreshold {
+		dst, err := minio.NewDestinationInfo(dstBucket, dstKey, nil, nil)
+		if err != nil {
+			return err
+		}
+		src := minio.NewSourceInfo(srcBucket, srcKey, nil)
+		if err := b.client.CopyObject(dst, src); err != nil {
+			return classifyError(err)
+		}
+		return nil
+	}
+
+	return b.multipartCopy(srcBucket, srcKey, dstBucket, dstKey, info.Size)
+}
+
+func (b *Bucket) multipartCopy(srcBucket, srcKey, dstBucket, dstKey string, size int64) error {
+	core := &minio.Core{Client: b.client}
+
+	uploadID, err := core.NewMultipartUpload(dstBucket, dstKey, minio.PutObjectOptions{})
+	if err != nil {
+		return classifyError(err)
+	}
+
+	var parts []minio.CompletePart
+	partNumber := 1
+	for offset := int64(0); offset < size; offset += copyPartSize {
+		length := copyPartSize
+		if remaining := size - offset; remaining < int64(length) {
+			length = int(remaining)
+		}
+		part, err := core.CopyObjectPart(srcBucket, srcKey, dstBucket, dstKey, uploadID, partNumber, offset, int64(length), nil)
+		if err != nil {
+			if abortErr := core.AbortMultipartUpload(dstBucket, dstKey, uploadID); abortErr != nil {
+				return classifyError(fmt.Errorf("copy part %d failed: %w; aborting upload also failed: %v", partNumber, err, abortErr))
+			}
+			return classifyError(fmt.Errorf("copy part %d failed: %w", partNumber, err))
+		}
+		parts = append(parts, part)
+		partNumber++
+	}
+
+	if _, err := core.CompleteMultipartUpload(dstBucket, dstKey, uploadID, parts); err != nil {
+		return classifyError(err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package objstore
+
+import (
+	"errors"
+
+	"github.com/minio/minio-go/v6"
+)
+
+// ErrorKind classifies an object storage error so callers can decide how (or whether) to retry.
+type ErrorKind int
+
+const (
+	// ErrOther is any error that doesn't fall into one of the more specific kinds below.
+	ErrOther ErrorKind = iota
+	// ErrNotFound means the bucket or object does not exist.
+	ErrNotFound
+	// ErrAccessDenied means the credentials are valid but not authorized for the operation.
+	ErrAccessDenied
+	// ErrThrottled means the request was rejected due to rate limiting and can be retried with backoff.
+	ErrThrottled
+)
+
+// Error wraps an error returned by the object storage backend together with its ErrorKind.
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// classifyError wraps err, if any, into an *Error with the ErrorKind derived from the backend's
+// S3 error code, also when that backend error is wrapped inside err. Callers that only care whether
+// an error occurred can keep treating the result as a plain error; callers that need to branch on
+// the kind can use errors.As with a target of type **objstore.Error.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) {
+		return &Error{Kind: ErrOther, Err: err}
+	}
+	switch resp.Code {
+	case "NoSuchKey", "NoSuchBucket", "NoSuchUpload":
+		return &Error{Kind: ErrNotFound, Err: err}
+	case "AccessDenied":
+		return &Error{Kind: ErrAccessDenied, Err: err}
+	case "SlowDown", "TooManyRequests", "ServiceUnavailable":
+		return &Error{Kind: ErrThrottled, Err: err}
+	default:
+		return &Error{Kind: ErrOther, Err: err}
+	}
+}
@@ -0,0 +1,118 @@
+package objstore
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/minio/minio-go/v6"
+)
+
+// LifecycleRule is one rule of a bucket lifecycle policy. A zero *Days field means that part of
+// the rule is left out of the policy, e.g. ExpirationDays == 0 means the rule doesn't expire
+// current object versions at all.
+type LifecycleRule struct {
+	ID      string
+	Prefix  string
+	Enabled bool
+
+	ExpirationDays                     int
+	NoncurrentVersionExpirationDays    int
+	AbortIncompleteMultipartUploadDays int
+}
+
+// BucketOptions configures EnsureBucket.
+//
+// KNOWN GAP, needs maintainer sign-off: there is deliberately no CORS option here, even though the
+// original request asked for CORS policy management alongside the lifecycle policy. minio-go v6,
+// which this package is built on, has no API for bucket CORS configuration, so there is nothing
+// EnsureBucket could apply it through. Configure CORS out of band (console/CLI) for now; closing
+// this gap needs either a client upgrade to a minio-go version that supports it, or a raw signed
+// request against the S3 CORS subresource added to this package.
+type BucketOptions struct {
+	// Region is passed to MakeBucket if the bucket doesn't exist yet.
+	Region string
+	// Lifecycle, if non-empty, is applied as the bucket's full lifecycle policy.
+	Lifecycle []LifecycleRule
+}
+
+// EnsureBucket idempotently creates the bucket name if it doesn't exist yet and applies opts'
+// lifecycle policy. It always returns a *Bucket for name, even when it already existed.
+func EnsureBucket(ctx context.Context, client *minio.Client, name string, opts BucketOptions) (*Bucket, error) {
+	exists, err := client.BucketExists(name)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	if !exists {
+		if err := client.MakeBucket(name, opts.Region); err != nil {
+			return nil, classifyError(err)
+		}
+	}
+
+	if len(opts.Lifecycle) > 0 {
+		policy, err := marshalLifecyclePolicy(opts.Lifecycle)
+		if err != nil {
+			return nil, fmt.Errorf("objstore: building lifecycle policy for bucket %q: %w", name, err)
+		}
+		if err := client.SetBucketLifecycle(name, policy); err != nil {
+			return nil, classifyError(err)
+		}
+	}
+
+	return NewBucket(client, name), nil
+}
+
+// lifecyclePolicyXML and friends mirror the subset of the S3 LifecycleConfiguration XML schema
+// that LifecycleRule exposes. minio-go v6's SetBucketLifecycle takes the policy as a raw XML
+// string rather than a typed struct, so we build and marshal it ourselves.
+type lifecyclePolicyXML struct {
+	XMLName xml.Name           `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRuleXML `xml:"Rule"`
+}
+
+type lifecycleRuleXML struct {
+	ID                             string                      `xml:"ID"`
+	Prefix                         string                      `xml:"Prefix"`
+	Status                         string                      `xml:"Status"`
+	Expiration                     *lifecycleExpirationXML     `xml:"Expiration,omitempty"`
+	NoncurrentVersionExpiration    *lifecycleNoncurrentExpXML  `xml:"NoncurrentVersionExpiration,omitempty"`
+	AbortIncompleteMultipartUpload *lifecycleAbortMultipartXML `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+type lifecycleExpirationXML struct {
+	Days int `xml:"Days"`
+}
+
+type lifecycleNoncurrentExpXML struct {
+	NoncurrentDays int `xml:"NoncurrentDays"`
+}
+
+type lifecycleAbortMultipartXML struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+func marshalLifecyclePolicy(rules []LifecycleRule) (string, error) {
+	doc := lifecyclePolicyXML{}
+	for _, r := range rules {
+		status := "Disabled"
+		if r.Enabled {
+			status = "Enabled"
+		}
+		rule := lifecycleRuleXML{ID: r.ID, Prefix: r.Prefix, Status: status}
+		if r.ExpirationDays > 0 {
+			rule.Expiration = &lifecycleExpirationXML{Days: r.ExpirationDays}
+		}
+		if r.NoncurrentVersionExpirationDays > 0 {
+			rule.NoncurrentVersionExpiration = &lifecycleNoncurrentExpXML{NoncurrentDays: r.NoncurrentVersionExpirationDays}
+		}
+		if r.AbortIncompleteMultipartUploadDays > 0 {
+			rule.AbortIncompleteMultipartUpload = &lifecycleAbortMultipartXML{DaysAfterInitiation: r.AbortIncompleteMultipartUploadDays}
+		}
+		doc.Rules = append(doc.Rules, rule)
+	}
+	raw, err := xml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
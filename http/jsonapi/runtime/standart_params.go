@@ -4,6 +4,9 @@
 package runtime
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -39,6 +42,34 @@ type ValueSanitizer interface {
 	SanitizeValue(fieldName string, value string) (interface{}, error)
 }
 
+// OperatorSanitizer is an optional extension of ValueSanitizer that lets implementers reject
+// filter operators that don't make sense for a given column, e.g. "like" on an integer column.
+// If a ValueSanitizer passed to FilterFromRequest also implements OperatorSanitizer, every filter
+// operator is checked against it before the filter is applied; a ValueSanitizer that doesn't
+// implement it allows every operator listed in filterOperators.
+type OperatorSanitizer interface {
+	// SanitizeOperator returns whether operator is allowed for the column fieldName
+	SanitizeOperator(fieldName string, operator string) bool
+}
+
+// filterOperators maps the operator suffix accepted in filter[field][op]=value to the SQL it
+// renders as. "in", "nin", "isnull" and "between" are handled separately in applyFilterClause
+// because they don't translate to a single "field op ?" comparison.
+var filterOperators = map[string]string{
+	"eq":      "=",
+	"ne":      "!=",
+	"gt":      ">",
+	"gte":     ">=",
+	"lt":      "<",
+	"lte":     "<=",
+	"like":    "LIKE",
+	"ilike":   "ILIKE",
+	"in":      "IN",
+	"nin":     "NOT IN",
+	"isnull":  "IS NULL",
+	"between": "BETWEEN",
+}
+
 // ColumnMapper maps the name of a filter or sorting parameter to a database column name
 type ColumnMapper interface {
 	// Map maps the value, this function decides if the value is allowed and translates it to a database column name,
@@ -84,16 +115,181 @@ func PaginationFromRequest(r *http.Request) (QueryOption, error) {
 	}
 
 	return func(query *orm.Query) *orm.Query {
-		if pageNr == 0 {
-			query.Offset(0)
-		} else {
-			query.Offset((pageSize * pageNr) - 1)
-		}
+		query.Offset(pageSize * pageNr)
 		query.Limit(pageSize)
 		return query
 	}, nil
 }
 
+// NextTokenFunc builds the opaque cursor token for the page following the one
+// that was just fetched. lastRowValues must contain the values of the sort
+// columns (in the same order as they were requested) taken from the last row
+// of the result set. If the result set was empty (no more pages), NextTokenFunc
+// should not be called.
+type NextTokenFunc func(lastRowValues []interface{}) (string, error)
+
+// cursorToken is the JSON payload that gets base64-encoded into the opaque
+// page[cursor] token handed to and accepted back from the client.
+type cursorToken struct {
+	Sort   []string      `json:"sort"`
+	Values []interface{} `json:"values"`
+	Dir    string        `json:"dir"`
+}
+
+// CursorPaginationFromRequest extracts cursor based pagination query parameters (page[cursor], page[size])
+// and returns a QueryOption that applies a keyset condition plus limit to the query, and a NextTokenFunc
+// that encodes the sort-key values of the last returned row into the cursor token for the next page.
+// Unlike PaginationFromRequest, which uses an offset that gets slower and less stable the deeper a client
+// pages, cursor pagination keeps every page equally fast and immune to rows shifting under concurrent writes.
+// The sort columns encoded in the cursor must match the columns produced by the request's "sort" parameter
+// (see SortingFromRequest); CursorPaginationFromRequest returns an error if a client supplied cursor was
+// issued for a different sort order. Mixed ascending/descending sort columns are not supported, since the
+// keyset comparison requires a single direction across the whole tuple.
+func CursorPaginationFromRequest(r *http.Request, modelMapping ColumnMapper) (QueryOption, NextTokenFunc, error) {
+	nop := func(query *orm.Query) *orm.Query { return query }
+
+	sizeStr := r.URL.Query().Get("page[size]")
+	if sizeStr == "" {
+		return nop, nil, nil
+	}
+	pageSize, err := strconv.Atoi(sizeStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if (pageSize < cfg.MinPageSize) || (pageSize > cfg.MaxPageSize) {
+		return nil, nil, fmt.Errorf("invalid pagesize not between min. and max. value, min: %d, max: %d", cfg.MinPageSize, cfg.MaxPageSize)
+	}
+
+	sortCols, dir, err := cursorSortFromRequest(r, modelMapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cursorValues []interface{}
+	if cursorStr := r.URL.Query().Get("page[cursor]"); cursorStr != "" {
+		tok, err := decodeCursorToken(cursorStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid page[cursor]: %w", err)
+		}
+		if !equalStrings(tok.Sort, sortCols) || tok.Dir != dir {
+			return nil, nil, fmt.Errorf("page[cursor] was issued for a different sort order")
+		}
+		cursorValues = tok.Values
+	}
+
+	queryOption := func(query *orm.Query) *orm.Query {
+		for _, col := range sortCols {
+			query.Order(col + " " + strings.ToUpper(dir))
+		}
+		if len(cursorValues) > 0 {
+			op := ">"
+			if dir == "desc" {
+				op = "<"
+			}
+			tuple := "(" + strings.Join(sortCols, ", ") + ")"
+			placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(cursorValues)), ", ") + ")"
+			query.Where(tuple+" "+op+" "+placeholders, cursorValues...)
+		}
+		query.Limit(pageSize + 1)
+		return query
+	}
+
+	nextToken := func(lastRowValues []interface{}) (string, error) {
+		return encodeCursorToken(cursorToken{Sort: sortCols, Values: lastRowValues, Dir: dir})
+	}
+	return queryOption, nextToken, nil
+}
+
+// cursorSortFromRequest parses the "sort" query parameter the same way SortingFromRequest does, but
+// additionally returns the single direction shared by all sort columns, as required for keyset pagination.
+func cursorSortFromRequest(r *http.Request, modelMapping ColumnMapper) ([]string, string, error) {
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		return nil, "", fmt.Errorf("cursor pagination requires a sort parameter")
+	}
+	var cols []string
+	dir := "asc"
+	haveDir := false
+	for _, val := range strings.Split(sort, ",") {
+		if val == "" {
+			continue
+		}
+		valDir := "asc"
+		if strings.HasPrefix(val, "-") {
+			valDir = "desc"
+		}
+		val = strings.TrimPrefix(val, "-")
+		if !haveDir {
+			dir = valDir
+			haveDir = true
+		} else if valDir != dir {
+			return nil, "", fmt.Errorf("cursor pagination requires all sort columns to share the same direction")
+		}
+		key, isValid := modelMapping.Map(val)
+		if !isValid {
+			return nil, "", fmt.Errorf("invalid sort parameter: %q", val)
+		}
+		cols = append(cols, key)
+	}
+	if len(cols) == 0 {
+		return nil, "", fmt.Errorf("cursor pagination requires a sort parameter")
+	}
+	return cols, dir, nil
+}
+
+// decodeCursorToken decodes a cursor token, taking care to decode its Values with json.Number
+// instead of the default float64 so an int64/bigint sort key doesn't lose precision above 2^53.
+func decodeCursorToken(s string) (cursorToken, error) {
+	var tok cursorToken
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return tok, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&tok); err != nil {
+		return tok, err
+	}
+	for i, v := range tok.Values {
+		if num, ok := v.(json.Number); ok {
+			tok.Values[i] = numberFromJSON(num)
+		}
+	}
+	return tok, nil
+}
+
+// numberFromJSON converts a json.Number back into an int64 where possible, falling back to a
+// float64 and finally to its string form, so integer sort keys survive the cursor token intact.
+func numberFromJSON(num json.Number) interface{} {
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+	if f, err := num.Float64(); err == nil {
+		return f
+	}
+	return string(num)
+}
+
+func encodeCursorToken(tok cursorToken) (string, error) {
+	raw, err := json.Marshal(tok)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // SortingFromRequest adds sorting to query based on the request query parameter
 // Database model and response type may differ, so the mapper allows to map the name of  field from the request
 // to a database column name
@@ -139,52 +335,136 @@ func SortingFromRequest(r *http.Request, modelMapping ColumnMapper) (QueryOption
 	return sortingFilterOption, nil
 }
 
-// FilterFromRequest adds filter to a query based on the request query parameter
-// filter[name]=val1,val2 results in name IN (val1, val2), filter[name]=val results in name=val
+// filterClause is one parsed filter[field][op]=value(s) entry, already mapped to its database
+// column and sanitized values. Multiple clauses are ANDed together by filterQueryOption.
+type filterClause struct {
+	field  string
+	op     string
+	values []interface{}
+}
+
+// FilterFromRequest adds filter to a query based on the request query parameter.
+// filter[name]=val1,val2 results in name IN (val1, val2), filter[name]=val results in name=val.
+// filter[name][]=val1&filter[name][]=val2, the array syntax used by axios, Rails and jQuery to
+// serialize arrays, is accepted the same way as the comma separated form, and the two can be mixed,
+// e.g. filter[name][]=val1,val2&filter[name][]=val3. A repeated non-array key, e.g.
+// filter[name]=val1&filter[name]=val2, is merged into the same IN list rather than the last
+// occurrence winning.
+// An operator can be given as a second bracket group, e.g. filter[price][gte]=10,
+// filter[name][like]=foo%, filter[deleted_at][isnull]=true or filter[tags][nin]=a,b; the supported
+// operators are listed in filterOperators (eq, ne, gt, gte, lt, lte, like, ilike, in, nin, isnull,
+// between). Multiple filter[field][op] parameters are ANDed together, also across different fields.
 // Database model and response type may differ, so the mapper allows to map the name of field from the request
 // to a database column name and the sanitizer allows to correct type of the value and sanitize it.
+// If sanitizer also implements OperatorSanitizer, every operator is checked against it.
 // Will always return a QueryOptions function with all valid filters (can be a nop)
 // if any filter are invalid a error with a list of all invalid filters is returned
 func FilterFromRequest(r *http.Request, modelMapping ColumnMapper, sanitizer ValueSanitizer) (QueryOption, error) {
-	filter := make(map[string][]interface{})
+	clauses, invalidFilter := parseFilterClauses(r, modelMapping, sanitizer)
+
+	filterQueryOption := func(query *orm.Query) *orm.Query {
+		for _, c := range clauses {
+			applyFilterClause(query, c)
+		}
+		return query
+	}
+
+	if len(invalidFilter) != 0 {
+		return filterQueryOption, fmt.Errorf("at least one filter parameter is not valid: %q", strings.Join(invalidFilter, ","))
+	}
+	return filterQueryOption, nil
+}
+
+// parseFilterClauses does the actual parsing and validation work for FilterFromRequest, split out
+// so it can be unit tested without needing a real *orm.Query.
+func parseFilterClauses(r *http.Request, modelMapping ColumnMapper, sanitizer ValueSanitizer) ([]filterClause, []string) {
+	var clauses []filterClause
 	var invalidFilter []string
 	for queryName, queryValues := range r.URL.Query() {
 		if !(strings.HasPrefix(queryName, "filter[") && strings.HasSuffix(queryName, "]")) {
 			continue
 		}
-		key, isValid := getFilterKey(queryName, modelMapping)
+		fieldParam, op := splitFilterQueryName(queryName)
+		if op == "" {
+			op = "eq"
+		}
+		if _, isValidOp := filterOperators[op]; !isValidOp {
+			invalidFilter = append(invalidFilter, queryName)
+			continue
+		}
+		key, isValid := modelMapping.Map(fieldParam)
 		if !isValid {
-			invalidFilter = append(invalidFilter, key)
+			invalidFilter = append(invalidFilter, fieldParam)
+			continue
+		}
+		if opSanitizer, ok := sanitizer.(OperatorSanitizer); ok && !opSanitizer.SanitizeOperator(key, op) {
+			invalidFilter = append(invalidFilter, queryName)
 			continue
 		}
 		filterValues, isValid := getFilterValues(key, queryValues, sanitizer)
 		if !isValid {
-			invalidFilter = append(invalidFilter, key)
+			invalidFilter = append(invalidFilter, fieldParam)
 			continue
 		}
-		filter[key] = filterValues
+		if (op == "between" && len(filterValues) != 2) || (op == "isnull" && len(filterValues) != 1) {
+			invalidFilter = append(invalidFilter, queryName)
+			continue
+		}
+		clauses = append(clauses, filterClause{field: key, op: op, values: filterValues})
 	}
+	return clauses, invalidFilter
+}
 
-	filterQueryOption := func(query *orm.Query) *orm.Query {
-		for name, filterValues := range filter {
-			if len(filterValues) == 0 {
-				continue
-			}
-
-			if len(filterValues) == 1 {
-				query.Where(name+" = ?", filterValues[0])
-				fmt.Printf("%s = %s", name, filterValues[0])
-				continue
-			}
-			query.Where(name+" IN (?)", pg.In(filterValues))
+// applyFilterClause translates one filterClause into the matching go-pg Where call.
+func applyFilterClause(query *orm.Query, c filterClause) {
+	if len(c.values) == 0 {
+		return
+	}
+	switch c.op {
+	case "isnull":
+		if isTruthy(c.values[0]) {
+			query.Where(c.field + " IS NULL")
+		} else {
+			query.Where(c.field + " IS NOT NULL")
+		}
+	case "in":
+		query.Where(c.field+" IN (?)", pg.In(c.values))
+	case "nin":
+		query.Where(c.field+" NOT IN (?)", pg.In(c.values))
+	case "between":
+		query.Where(c.field+" BETWEEN ? AND ?", c.values[0], c.values[1])
+	case "eq":
+		if len(c.values) == 1 {
+			query.Where(c.field+" = ?", c.values[0])
+		} else {
+			query.Where(c.field+" IN (?)", pg.In(c.values))
+		}
+	case "ne":
+		if len(c.values) == 1 {
+			query.Where(c.field+" != ?", c.values[0])
+		} else {
+			query.Where(c.field+" NOT IN (?)", pg.In(c.values))
+		}
+	default:
+		sqlOp := filterOperators[c.op]
+		for _, v := range c.values {
+			query.Where(c.field+" "+sqlOp+" ?", v)
 		}
-		return query
 	}
+}
 
-	if len(invalidFilter) != 0 {
-		return filterQueryOption, fmt.Errorf("at least one filter parameter is not valid: %q", strings.Join(invalidFilter, ","))
+// isTruthy interprets a sanitized filter value (bool or string) as a boolean, used by the
+// "isnull" operator to decide between IS NULL and IS NOT NULL.
+func isTruthy(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		b, _ := strconv.ParseBool(val)
+		return b
+	default:
+		return false
 	}
-	return filterQueryOption, nil
 }
 
 // FilterPagingSortingFromRequest adds filter, sorting and pagination to a query based on the request query parameters
@@ -209,14 +489,247 @@ func FilterPagingSortingFromRequest(r *http.Request, modelMapping ColumnMapper,
 	}, nil
 }
 
-func getFilterKey(queryName string, modelMapping ColumnMapper) (string, bool) {
-	field := strings.TrimPrefix(queryName, "filter[")
-	field = strings.TrimSuffix(field, "]")
-	mapped, isValid := modelMapping.Map(field)
-	if !isValid {
-		return field, false
+// Searcher declares how SearchFromRequest should turn the conventional q= query parameter into
+// a full text search predicate. Columns lists the columns (already database column names, not
+// request field names) that are searched. Language is the Postgres text search configuration to
+// pass to plainto_tsquery/to_tsvector, e.g. "english"; an empty Language makes SearchFromRequest
+// fall back to a simple "column ILIKE '%q%'" predicate across Columns instead of using FTS.
+// TSVectorColumn, if non-empty, names a precomputed tsvector column to search against instead of
+// building one from Columns at query time.
+type Searcher interface {
+	// Columns returns the database columns that q= is searched across
+	Columns() []string
+	// Language returns the Postgres text search configuration, or "" to use the ILIKE fallback
+	Language() string
+	// TSVectorColumn returns the name of a precomputed tsvector column, or "" to build one from Columns
+	TSVectorColumn() string
+}
+
+// ilikeSearcher is the Searcher returned by NewILikeSearcher: it always uses the ILIKE fallback.
+type ilikeSearcher struct {
+	cols []string
+}
+
+// NewILikeSearcher returns a Searcher that matches q= against cols using "ILIKE '%q%'", ORed
+// together. It doesn't require a text search configuration or index and is meant for the common
+// case of a handful of short text columns on small to medium sized tables.
+func NewILikeSearcher(cols ...string) Searcher {
+	return &ilikeSearcher{cols: cols}
+}
+
+func (s *ilikeSearcher) Columns() []string      { return s.cols }
+func (s *ilikeSearcher) Language() string       { return "" }
+func (s *ilikeSearcher) TSVectorColumn() string { return "" }
+
+// SearchFromRequest reads the conventional q= query parameter and returns a QueryOption that
+// restricts the query to rows matching it, using the full text search or ILIKE strategy
+// described by searcher. Returns a nop QueryOption if q= is empty or missing.
+func SearchFromRequest(r *http.Request, searcher Searcher) (QueryOption, error) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		return func(query *orm.Query) *orm.Query { return query }, nil
+	}
+	cols := searcher.Columns()
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("searcher declares no searchable columns")
+	}
+
+	if searcher.Language() == "" {
+		pattern := "%" + q + "%"
+		return func(query *orm.Query) *orm.Query {
+			query.WhereGroup(func(wq *orm.Query) (*orm.Query, error) {
+				for i, col := range cols {
+					if i == 0 {
+						wq = wq.Where(col+" ILIKE ?", pattern)
+					} else {
+						wq = wq.WhereOr(col+" ILIKE ?", pattern)
+					}
+				}
+				return wq, nil
+			})
+			return query
+		}, nil
+	}
+
+	if col := searcher.TSVectorColumn(); col != "" {
+		return func(query *orm.Query) *orm.Query {
+			query.Where(col+" @@ plainto_tsquery(?, ?)", searcher.Language(), q)
+			return query
+		}, nil
+	}
+
+	tsvector := "to_tsvector(?, " + strings.Join(cols, " || ' ' || ") + ")"
+	return func(query *orm.Query) *orm.Query {
+		query.Where(tsvector+" @@ plainto_tsquery(?, ?)", searcher.Language(), searcher.Language(), q)
+		return query
+	}, nil
+}
+
+// RelationMapper maps a dotted relationship include path (e.g. "rel1.sub") from a request to the
+// corresponding dotted go-pg model field path (e.g. "Rel1.Sub"), analogous to ColumnMapper but for
+// IncludeFromRequest. MaxDepth bounds how many dotted segments a path may have, so a request can't
+// force unbounded joins.
+type RelationMapper interface {
+	// Map maps the dotted include path, this function decides if the path is allowed and translates it
+	// to the dotted go-pg relation path, the function returns the go-pg relation path and a bool that
+	// indicates that the path is allowed and mapped
+	Map(path string) (string, bool)
+	// MaxDepth returns the maximum number of dotted segments an include path may have
+	MaxDepth() int
+}
+
+// MapRelationMapper is a very easy RelationMapper implementation based on a map which contains all
+// allowed include paths
+type MapRelationMapper struct {
+	mapping  map[string]string
+	maxDepth int
+}
+
+// NewMapRelationMapper returns a MapRelationMapper for a specific map and maximum include depth
+func NewMapRelationMapper(mapping map[string]string, maxDepth int) *MapRelationMapper {
+	return &MapRelationMapper{mapping: mapping, maxDepth: maxDepth}
+}
+
+// Map returns the mapped relation path and if it is valid based on a map
+func (m *MapRelationMapper) Map(path string) (string, bool) {
+	val, isValid := m.mapping[path]
+	return val, isValid
+}
+
+// MaxDepth returns the configured maximum include depth
+func (m *MapRelationMapper) MaxDepth() int {
+	return m.maxDepth
+}
+
+// FieldsFromRequest honors the JSON:API fields[resourceType]=a,b,c sparse fieldset parameter: it
+// returns a QueryOption that restricts the query to the primary key plus the requested (validated,
+// mapped) columns, and the accepted request-side field names so the caller's JSON:API encoder can
+// prune the response to match. Returns a nop QueryOption and nil fields if fields[resourceType] is
+// not present in the request.
+func FieldsFromRequest(r *http.Request, resourceType string, mapping ColumnMapper) (QueryOption, []string, error) {
+	nop := func(query *orm.Query) *orm.Query { return query }
+	raw := r.URL.Query().Get("fields[" + resourceType + "]")
+	if raw == "" {
+		return nop, nil, nil
+	}
+
+	var invalid, fields, columns []string
+	for _, f := range strings.Split(raw, ",") {
+		if f == "" {
+			continue
+		}
+		col, isValid := mapping.Map(f)
+		if !isValid {
+			invalid = append(invalid, f)
+			continue
+		}
+		fields = append(fields, f)
+		columns = append(columns, col)
+	}
+
+	fieldsOption := func(query *orm.Query) *orm.Query {
+		if len(columns) == 0 {
+			return query
+		}
+		requested := make(map[string]bool, len(columns))
+		for _, c := range columns {
+			requested[c] = true
+		}
+		for _, pk := range query.TableModel().Table().PKs {
+			col := string(pk.SQLName)
+			if !requested[col] {
+				query.Column(col)
+			}
+		}
+		query.Column(columns...)
+		return query
+	}
+
+	if len(invalid) != 0 {
+		return fieldsOption, fields, fmt.Errorf("at least one field parameter is not valid: %q", strings.Join(invalid, ","))
+	}
+	return fieldsOption, fields, nil
+}
+
+// IncludeFromRequest honors the JSON:API include=rel1,rel2.sub relationship include parameter: it
+// returns a QueryOption that adds a go-pg Relation() call for every requested, validated include
+// path, and the accepted request-side include paths so the caller's JSON:API encoder can prune the
+// response to match. Returns a nop QueryOption and nil includes if include is not present.
+func IncludeFromRequest(r *http.Request, relMapping RelationMapper) (QueryOption, []string, error) {
+	nop := func(query *orm.Query) *orm.Query { return query }
+	raw := r.URL.Query().Get("include")
+	if raw == "" {
+		return nop, nil, nil
 	}
-	return mapped, true
+
+	var invalid, includes, relations []string
+	for _, path := range strings.Split(raw, ",") {
+		if path == "" {
+			continue
+		}
+		if depth := strings.Count(path, ".") + 1; depth > relMapping.MaxDepth() {
+			invalid = append(invalid, path)
+			continue
+		}
+		relation, isValid := relMapping.Map(path)
+		if !isValid {
+			invalid = append(invalid, path)
+			continue
+		}
+		includes = append(includes, path)
+		relations = append(relations, relation)
+	}
+
+	includeOption := func(query *orm.Query) *orm.Query {
+		for _, relation := range relations {
+			query.Relation(relation)
+		}
+		return query
+	}
+
+	if len(invalid) != 0 {
+		return includeOption, includes, fmt.Errorf("at least one include parameter is not valid: %q", strings.Join(invalid, ","))
+	}
+	return includeOption, includes, nil
+}
+
+// ListFromRequest combines sorting, filtering, full text search, sparse fieldsets, relationship
+// includes and pagination, the concerns most list endpoints need, into a single QueryOption. It
+// supersedes FilterPagingSortingFromRequest for endpoints that also accept q=, fields[resourceType]
+// and include. Besides the QueryOption it returns the accepted sparse fieldset and include paths,
+// so the caller's JSON:API encoder can prune the response to match what was actually fetched.
+func ListFromRequest(r *http.Request, resourceType string, modelMapping ColumnMapper, sanitizer ValueSanitizer, searcher Searcher, relMapping RelationMapper) (QueryOption, []string, []string, error) {
+	listOption, err := FilterPagingSortingFromRequest(r, modelMapping, sanitizer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	searchOption, err := SearchFromRequest(r, searcher)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	fieldsOption, fields, err := FieldsFromRequest(r, resourceType, modelMapping)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	includeOption, includes, err := IncludeFromRequest(r, relMapping)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return func(query *orm.Query) *orm.Query {
+		q := searchOption(listOption(query))
+		q = fieldsOption(q)
+		return includeOption(q)
+	}, fields, includes, nil
+}
+
+// splitFilterQueryName splits a "filter[field]" or "filter[field][op]" query parameter name into
+// its field and operator parts. op is "" when no operator bracket group was given.
+func splitFilterQueryName(queryName string) (field, op string) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(queryName, "filter["), "]")
+	if idx := strings.Index(inner, "]["); idx >= 0 {
+		return inner[:idx], inner[idx+2:]
+	}
+	return inner, ""
 }
 
 func getFilterValues(fieldName string, queryValues []string, sanitizer ValueSanitizer) ([]interface{}, bool) {
@@ -232,4 +745,4 @@ func getFilterValues(fieldName string, queryValues []string, sanitizer ValueSani
 		}
 	}
 	return filterValues, true
-}
\ No newline at end of file
+}
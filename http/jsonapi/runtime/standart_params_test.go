@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// identityMapper maps every field name to itself, accepting anything.
+type identityMapper struct{}
+
+func (identityMapper) Map(value string) (string, bool) { return value, true }
+
+// identitySanitizer returns every value unchanged.
+type identitySanitizer struct{}
+
+func (identitySanitizer) SanitizeValue(fieldName string, value string) (interface{}, error) {
+	return value, nil
+}
+
+func filterClausesFor(t *testing.T, rawQuery string) ([]filterClause, []string) {
+	t.Helper()
+	u := &url.URL{RawQuery: rawQuery}
+	r := &http.Request{URL: u}
+	return parseFilterClauses(r, identityMapper{}, identitySanitizer{})
+}
+
+func valuesOf(values []interface{}) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.(string))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestParseFilterClausesArrayAndRepeatedKeySyntax(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawQuery   string
+		wantValues []string
+		wantOp     string
+	}{
+		{
+			name:       "single value",
+			rawQuery:   "filter[name]=foo",
+			wantValues: []string{"foo"},
+			wantOp:     "eq",
+		},
+		{
+			name:       "comma list",
+			rawQuery:   "filter[name]=foo,bar",
+			wantValues: []string{"bar", "foo"},
+			wantOp:     "eq",
+		},
+		{
+			name:       "bracket array list",
+			rawQuery:   "filter[name][]=foo&filter[name][]=bar",
+			wantValues: []string{"bar", "foo"},
+			wantOp:     "eq",
+		},
+		{
+			name:       "mixed bracket array and comma list",
+			rawQuery:   "filter[name][]=foo,bar&filter[name][]=baz",
+			wantValues: []string{"bar", "baz", "foo"},
+			wantOp:     "eq",
+		},
+		{
+			name:       "duplicate non-bracket keys are merged",
+			rawQuery:   "filter[name]=foo&filter[name]=bar",
+			wantValues: []string{"bar", "foo"},
+			wantOp:     "eq",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clauses, invalid := filterClausesFor(t, tt.rawQuery)
+			if len(invalid) != 0 {
+				t.Fatalf("unexpected invalid filters: %v", invalid)
+			}
+			if len(clauses) != 1 {
+				t.Fatalf("expected exactly one filter clause, got %d", len(clauses))
+			}
+			c := clauses[0]
+			if c.field != "name" {
+				t.Fatalf("expected field %q, got %q", "name", c.field)
+			}
+			if c.op != tt.wantOp {
+				t.Fatalf("expected op %q, got %q", tt.wantOp, c.op)
+			}
+			if got := valuesOf(c.values); !reflect.DeepEqual(got, tt.wantValues) {
+				t.Fatalf("expected values %v, got %v", tt.wantValues, got)
+			}
+		})
+	}
+}